@@ -0,0 +1,54 @@
+// Copyright (c) 2025 Visvasity LLC
+
+// Package kvetcd implements the kv.Database, kv.Transaction and kv.Snapshot
+// interfaces on top of etcd v3, so that applications written against the kv
+// package can run against a distributed etcd cluster.
+package kvetcd
+
+import (
+	"context"
+	"fmt"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/visvasity/kv"
+)
+
+// txnMarkerPrefix holds the well-known, reserved keyspace where committed
+// transaction ids are recorded. Commit uses this to detect -- after a
+// network error hides the RPC response -- whether a transaction it issued
+// actually went through, so that it never has to report a failure for a
+// transaction that in fact committed.
+const txnMarkerPrefix = "\x00kv/txn/"
+
+// Database implements the kv.Database interface on top of an etcd v3
+// client. Use NewDatabase to create one.
+type Database struct {
+	client *clientv3.Client
+}
+
+// NewDatabase returns a kv.Database backed by the given etcd client. The
+// client is not closed by the returned database; callers remain responsible
+// for its lifecycle.
+func NewDatabase(ctx context.Context, client *clientv3.Client) (kv.Database, error) {
+	if client == nil {
+		return nil, fmt.Errorf("etcd client must not be nil")
+	}
+	d := &Database{client: client}
+	return kv.DatabaseFrom[*Transaction, *Snapshot](d), nil
+}
+
+// NewTransaction begins a new optimistic, read-write transaction.
+func (d *Database) NewTransaction(ctx context.Context) (*Transaction, error) {
+	return newTransaction(d.client), nil
+}
+
+// NewSnapshot pins the current etcd revision and returns a read-only view of
+// the database as of that revision.
+func (d *Database) NewSnapshot(ctx context.Context) (*Snapshot, error) {
+	resp, err := d.client.Get(ctx, "nonexistent-key-used-to-read-header")
+	if err != nil {
+		return nil, fmt.Errorf("could not determine current revision: %w", err)
+	}
+	return newSnapshot(d.client, resp.Header.Revision), nil
+}
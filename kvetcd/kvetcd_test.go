@@ -0,0 +1,282 @@
+// Copyright (c) 2025 Visvasity LLC
+
+package kvetcd
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.etcd.io/etcd/server/v3/embed"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/visvasity/kv"
+	"github.com/visvasity/kv/kvutil"
+)
+
+// freePort returns a TCP port that is free at the time of the call, to keep
+// the embedded etcd instances below from colliding with each other or with
+// anything else on the test host.
+func freePort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen: %v", err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+func mustURL(t *testing.T, s string) url.URL {
+	t.Helper()
+	u, err := url.Parse(s)
+	if err != nil {
+		t.Fatalf("url.Parse(%q): %v", s, err)
+	}
+	return *u
+}
+
+// newTestDB starts a single-node, in-process etcd server for the lifetime of
+// the test and returns a kv.Database backed by it.
+func newTestDB(t *testing.T) kv.Database {
+	t.Helper()
+
+	cfg := embed.NewConfig()
+	cfg.Dir = t.TempDir()
+	cfg.LogLevel = "error"
+
+	clientURL := mustURL(t, "http://127.0.0.1:"+strconv.Itoa(freePort(t)))
+	peerURL := mustURL(t, "http://127.0.0.1:"+strconv.Itoa(freePort(t)))
+	cfg.ListenClientUrls = []url.URL{clientURL}
+	cfg.AdvertiseClientUrls = []url.URL{clientURL}
+	cfg.ListenPeerUrls = []url.URL{peerURL}
+	cfg.AdvertisePeerUrls = []url.URL{peerURL}
+	cfg.InitialCluster = cfg.InitialClusterFromName(cfg.Name)
+
+	e, err := embed.StartEtcd(cfg)
+	if err != nil {
+		t.Fatalf("embed.StartEtcd: %v", err)
+	}
+	t.Cleanup(e.Close)
+
+	select {
+	case <-e.Server.ReadyNotify():
+	case <-time.After(30 * time.Second):
+		t.Fatal("etcd server did not become ready in time")
+	}
+
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   []string{clientURL.String()},
+		DialTimeout: 10 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("clientv3.New: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	db, err := NewDatabase(context.Background(), client)
+	if err != nil {
+		t.Fatalf("NewDatabase: %v", err)
+	}
+	return db
+}
+
+func set(t *testing.T, ctx context.Context, db kv.Database, key, value string) {
+	t.Helper()
+	if err := kvutil.WithReadWriter(ctx, db, func(ctx context.Context, rw kv.ReadWriter) error {
+		return rw.Set(ctx, key, strings.NewReader(value))
+	}); err != nil {
+		t.Fatalf("Set(%q): %v", key, err)
+	}
+}
+
+func TestGetSetDelete(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	if err := kvutil.WithReader(ctx, db, func(ctx context.Context, r kv.Reader) error {
+		if _, err := r.Get(ctx, "missing"); err != os.ErrNotExist {
+			t.Errorf("Get(missing) = %v, want os.ErrNotExist", err)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	set(t, ctx, db, "k", "v1")
+
+	if err := kvutil.WithReader(ctx, db, func(ctx context.Context, r kv.Reader) error {
+		v, err := r.Get(ctx, "k")
+		if err != nil {
+			return err
+		}
+		b, _ := io.ReadAll(v)
+		if string(b) != "v1" {
+			t.Errorf("got %q, want v1", b)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := kvutil.WithReadWriter(ctx, db, func(ctx context.Context, rw kv.ReadWriter) error {
+		return rw.Delete(ctx, "k")
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := kvutil.WithReader(ctx, db, func(ctx context.Context, r kv.Reader) error {
+		if _, err := r.Get(ctx, "k"); err != os.ErrNotExist {
+			t.Errorf("Get(k) after delete = %v, want os.ErrNotExist", err)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTransactionConflictDetection(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	set(t, ctx, db, "k", "v1")
+
+	tx1, err := db.NewTransaction(ctx)
+	if err != nil {
+		t.Fatalf("NewTransaction: %v", err)
+	}
+	if _, err := tx1.Get(ctx, "k"); err != nil {
+		t.Fatalf("tx1.Get: %v", err)
+	}
+
+	// A concurrent writer modifies the same key before tx1 commits.
+	set(t, ctx, db, "k", "v2")
+
+	if err := tx1.Set(ctx, "k", strings.NewReader("v3")); err != nil {
+		t.Fatalf("tx1.Set: %v", err)
+	}
+	if err := tx1.Commit(ctx); err == nil {
+		t.Fatalf("tx1.Commit succeeded, want conflict error")
+	}
+}
+
+// TestAscendRejectsInvertedRange covers kv.go's Ranger contract: when beg
+// and end are both non-empty, beg must be less than or equal to end.
+func TestAscendRejectsInvertedRange(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	set(t, ctx, db, "a", "1")
+
+	if err := kvutil.WithReader(ctx, db, func(ctx context.Context, r kv.Reader) error {
+		var err error
+		var n int
+		for range r.Ascend(ctx, "z", "a", &err) {
+			n++
+		}
+		if err != os.ErrInvalid {
+			t.Errorf("Ascend(z, a) err = %v, want os.ErrInvalid", err)
+		}
+		if n != 0 {
+			t.Errorf("Ascend(z, a) yielded %d pairs, want 0", n)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := db.NewTransaction(ctx)
+	if err != nil {
+		t.Fatalf("NewTransaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+	var err2 error
+	for range tx.Ascend(ctx, "z", "a", &err2) {
+	}
+	if err2 != os.ErrInvalid {
+		t.Errorf("Transaction.Ascend(z, a) err = %v, want os.ErrInvalid", err2)
+	}
+}
+
+// TestAscendHidesCommitMarkers ensures the reserved txnMarkerPrefix keyspace
+// that Commit writes to never leaks out through a full Ascend scan.
+func TestAscendHidesCommitMarkers(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	set(t, ctx, db, "a", "1")
+	set(t, ctx, db, "b", "2")
+
+	if err := kvutil.WithReader(ctx, db, func(ctx context.Context, r kv.Reader) error {
+		var err error
+		var keys []string
+		for k := range r.Ascend(ctx, "", "", &err) {
+			keys = append(keys, k)
+		}
+		if err != nil {
+			return err
+		}
+		if got, want := strings.Join(keys, ","), "a,b"; got != want {
+			t.Errorf("Ascend keys = %q, want %q (commit markers leaked?)", got, want)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRangeExtKeysOnly(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	set(t, ctx, db, "a", "1")
+	set(t, ctx, db, "b", "2")
+
+	if err := kvutil.WithReader(ctx, db, func(ctx context.Context, r kv.Reader) error {
+		var err error
+		for k, v := range kvutil.GetRange(ctx, r, "", "", kvutil.RangeOptions{KeysOnly: true}, &err) {
+			b, _ := io.ReadAll(v)
+			if len(b) != 0 {
+				t.Errorf("KeysOnly returned non-empty value %q for key %q", b, k)
+			}
+		}
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestRangeExtLimitSkipsCommitMarkers is a regression test: RangeExt used to
+// pass beg straight through to etcd's native WithLimit, so a committed
+// transaction's "\x00kv/txn/"-prefixed marker key -- sorting before every
+// real key -- could consume part of a small Limit server-side before the
+// client-side reserved-key filter ever ran, silently truncating a full scan.
+func TestRangeExtLimitSkipsCommitMarkers(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	// A committed read-write transaction leaves a marker behind that sorts
+	// before "a" and "b".
+	set(t, ctx, db, "a", "1")
+	set(t, ctx, db, "b", "2")
+
+	if err := kvutil.WithReader(ctx, db, func(ctx context.Context, r kv.Reader) error {
+		var err error
+		var keys []string
+		for k := range kvutil.GetRange(ctx, r, "", "", kvutil.RangeOptions{Limit: 2}, &err) {
+			keys = append(keys, k)
+		}
+		if err != nil {
+			return err
+		}
+		if got, want := strings.Join(keys, ","), "a,b"; got != want {
+			t.Errorf("Limit-2 scan = %q, want %q (commit marker ate a Limit slot?)", got, want)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2025 Visvasity LLC
+
+package kvetcd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/visvasity/kv"
+	"github.com/visvasity/kv/kvutil"
+)
+
+func init() {
+	kv.Register("etcd", openDSN)
+}
+
+// openDSN implements kv.Opener for DSNs of the form
+// "etcd://host:2379/prefix?dial-timeout=5s". The host:port becomes the
+// single etcd endpoint, and a non-empty path namespaces every key under
+// that prefix via kvutil.Namespace.
+func openDSN(ctx context.Context, u *url.URL) (kv.Database, error) {
+	cfg := clientv3.Config{Endpoints: []string{u.Host}}
+
+	if s := u.Query().Get("dial-timeout"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dial-timeout %q: %w", s, err)
+		}
+		cfg.DialTimeout = d
+	}
+
+	client, err := clientv3.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := NewDatabase(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	if prefix := strings.Trim(u.Path, "/"); prefix != "" {
+		return kvutil.Namespace(db, prefix), nil
+	}
+	return db, nil
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2025 Visvasity LLC
+
+package kvetcd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"iter"
+	"os"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/visvasity/kv/kvutil"
+)
+
+// Snapshot implements the kv.Snapshot interface by pinning a single etcd
+// revision and routing all reads through it.
+type Snapshot struct {
+	client *clientv3.Client
+	rev    int64
+}
+
+func newSnapshot(client *clientv3.Client, rev int64) *Snapshot {
+	return &Snapshot{client: client, rev: rev}
+}
+
+// Get returns the value for the key as of the snapshot's pinned revision.
+func (s *Snapshot) Get(ctx context.Context, key string) (io.Reader, error) {
+	if key == "" {
+		return nil, os.ErrInvalid
+	}
+	resp, err := s.client.Get(ctx, key, clientv3.WithRev(s.rev))
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, os.ErrNotExist
+	}
+	return bytes.NewReader(resp.Kvs[0].Value), nil
+}
+
+// Ascend returns key-value pairs in the range in ascending order, as of the
+// snapshot's pinned revision.
+func (s *Snapshot) Ascend(ctx context.Context, beg, end string, errp *error) iter.Seq2[string, io.Reader] {
+	return s.RangeExt(ctx, beg, end, kvutil.RangeOptions{}, errp)
+}
+
+// Descend is similar to Ascend but iterates in descending order.
+func (s *Snapshot) Descend(ctx context.Context, beg, end string, errp *error) iter.Seq2[string, io.Reader] {
+	return s.RangeExt(ctx, beg, end, kvutil.RangeOptions{Reverse: true}, errp)
+}
+
+// RangeExt implements kvutil.RangerExt natively on top of etcd's own
+// pagination: Limit maps to clientv3.WithLimit and KeysOnly to
+// clientv3.WithKeysOnly, so callers that set KeysOnly never pay for values
+// they didn't ask for. beg is widened past the reserved keyspace before the
+// request ever reaches etcd, rather than filtering the response afterwards,
+// so that a server-side Limit always counts only real, user-visible keys.
+func (s *Snapshot) RangeExt(ctx context.Context, beg, end string, opts kvutil.RangeOptions, errp *error) iter.Seq2[string, io.Reader] {
+	return func(yield func(string, io.Reader) bool) {
+		if !validRange(beg, end) {
+			*errp = os.ErrInvalid
+			return
+		}
+
+		resp, err := etcdRange(ctx, s.client, s.rev, skipReservedKeyspace(beg), end, opts)
+		if err != nil {
+			*errp = err
+			return
+		}
+		for _, kv := range resp.Kvs {
+			if ctx.Err() != nil {
+				*errp = ctx.Err()
+				return
+			}
+			key := string(kv.Key)
+			if isReservedKey(key) {
+				continue
+			}
+			if !yield(key, bytes.NewReader(kv.Value)) {
+				return
+			}
+		}
+	}
+}
+
+// isReservedKey reports whether key belongs to the internal metadata
+// keyspace (such as txnMarkerPrefix) and must never be surfaced through
+// Ascend/Descend scans or included in a range's read-set. Get does not
+// check it.
+func isReservedKey(key string) bool {
+	return len(key) > 0 && key[0] == 0
+}
+
+// validRange reports whether beg/end satisfy kv.Ranger's contract: when both
+// are non-empty, beg must be less than or equal to end.
+func validRange(beg, end string) bool {
+	return beg == "" || end == "" || beg <= end
+}
+
+// reservedKeyspaceEnd is the exclusive end of the reserved keyspace: every
+// reserved key has a leading 0x00 byte, so the reserved keyspace is exactly
+// [0x00, reservedKeyspaceEnd).
+const reservedKeyspaceEnd = "\x01"
+
+// skipReservedKeyspace widens beg past the reserved keyspace when it would
+// otherwise overlap it, so that a caller's server-side Limit is never spent
+// on reserved keys that RangeExt would filter out anyway.
+func skipReservedKeyspace(beg string) string {
+	if beg == "" || beg[0] == 0 {
+		return reservedKeyspaceEnd
+	}
+	return beg
+}
+
+// Discard releases resources associated with the snapshot. Pinned revisions
+// held by etcd are reclaimed by its own compaction policy, so there is
+// nothing for Discard to do beyond satisfying the interface.
+func (s *Snapshot) Discard(ctx context.Context) error {
+	return nil
+}
+
+// etcdRange fetches the key-value pairs in [beg, end) as of rev, translating
+// the empty-end convention used by kv.Ranger into etcd's WithFromKey option
+// and opts into the matching native etcd range options.
+func etcdRange(ctx context.Context, client *clientv3.Client, rev int64, beg, end string, opts kvutil.RangeOptions) (*clientv3.GetResponse, error) {
+	rangeOpts := []clientv3.OpOption{clientv3.WithRev(rev)}
+	if end == "" {
+		rangeOpts = append(rangeOpts, clientv3.WithFromKey())
+	} else {
+		rangeOpts = append(rangeOpts, clientv3.WithRange(end))
+	}
+	if opts.Reverse {
+		rangeOpts = append(rangeOpts, clientv3.WithSort(clientv3.SortByKey, clientv3.SortDescend))
+	} else {
+		rangeOpts = append(rangeOpts, clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	}
+	if opts.Limit > 0 {
+		rangeOpts = append(rangeOpts, clientv3.WithLimit(int64(opts.Limit)))
+	}
+	if opts.KeysOnly {
+		rangeOpts = append(rangeOpts, clientv3.WithKeysOnly())
+	}
+	return client.Get(ctx, beg, rangeOpts...)
+}
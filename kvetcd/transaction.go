@@ -0,0 +1,340 @@
+// Copyright (c) 2025 Visvasity LLC
+
+package kvetcd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/visvasity/kv/kvutil"
+)
+
+type writeOp struct {
+	value  []byte
+	delete bool
+}
+
+// Transaction implements the kv.Transaction interface as an etcd STM-style
+// session: reads are buffered against a single pinned revision and writes are
+// buffered locally until Commit, which submits them all as one etcd Txn
+// guarded by per-key revision comparisons.
+type Transaction struct {
+	client *clientv3.Client
+
+	// id uniquely identifies this transaction's attempt to commit. It is
+	// written into txnMarkerPrefix+id as part of the same etcd Txn that
+	// applies the write-set, so that a retry after a lost response can look
+	// it up to learn whether the commit actually went through.
+	id string
+
+	mu         sync.Mutex
+	rev        int64 // pinned read revision; zero until the first read.
+	reads      map[string]int64
+	writes     map[string]*writeOp
+	committed  bool
+	rolledback bool
+}
+
+func newTransaction(client *clientv3.Client) *Transaction {
+	return &Transaction{
+		client: client,
+		id:     newTxnID(),
+		reads:  make(map[string]int64),
+		writes: make(map[string]*writeOp),
+	}
+}
+
+func newTxnID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("kvetcd: could not generate transaction id: %v", err))
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// ensureRev pins the transaction's read revision to the current etcd
+// revision the first time it is needed, so that all reads within the
+// transaction observe a single consistent point in time.
+func (t *Transaction) ensureRev(ctx context.Context) error {
+	if t.rev != 0 {
+		return nil
+	}
+	resp, err := t.client.Get(ctx, txnMarkerPrefix+t.id)
+	if err != nil {
+		return err
+	}
+	t.rev = resp.Header.Revision
+	return nil
+}
+
+// Get retrieves the value for key, preferring the transaction's own pending
+// writes over the value observed at the pinned read revision.
+func (t *Transaction) Get(ctx context.Context, key string) (io.Reader, error) {
+	if key == "" {
+		return nil, os.ErrInvalid
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if op, ok := t.writes[key]; ok {
+		if op.delete {
+			return nil, os.ErrNotExist
+		}
+		return bytes.NewReader(op.value), nil
+	}
+
+	if err := t.ensureRev(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := t.client.Get(ctx, key, clientv3.WithRev(t.rev))
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		t.reads[key] = 0
+		return nil, os.ErrNotExist
+	}
+	t.reads[key] = resp.Kvs[0].ModRevision
+	return bytes.NewReader(resp.Kvs[0].Value), nil
+}
+
+// Set buffers the key-value pair locally; it is applied to etcd only on
+// Commit.
+func (t *Transaction) Set(ctx context.Context, key string, value io.Reader) error {
+	if key == "" || value == nil {
+		return os.ErrInvalid
+	}
+	b, err := io.ReadAll(value)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.writes[key] = &writeOp{value: b}
+	return nil
+}
+
+// Delete buffers removal of the key locally; it is applied to etcd only on
+// Commit.
+func (t *Transaction) Delete(ctx context.Context, key string) error {
+	if key == "" {
+		return os.ErrInvalid
+	}
+	if _, err := t.Get(ctx, key); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.writes[key] = &writeOp{delete: true}
+	return nil
+}
+
+// Ascend returns key-value pairs in the range in ascending order, merging
+// the transaction's pending writes over the pinned read revision.
+func (t *Transaction) Ascend(ctx context.Context, beg, end string, errp *error) iter.Seq2[string, io.Reader] {
+	return t.rangeSeq(ctx, beg, end, false, errp)
+}
+
+// Descend is similar to Ascend but iterates in descending order.
+func (t *Transaction) Descend(ctx context.Context, beg, end string, errp *error) iter.Seq2[string, io.Reader] {
+	return t.rangeSeq(ctx, beg, end, true, errp)
+}
+
+func (t *Transaction) rangeSeq(ctx context.Context, beg, end string, descend bool, errp *error) iter.Seq2[string, io.Reader] {
+	return func(yield func(string, io.Reader) bool) {
+		if !validRange(beg, end) {
+			*errp = os.ErrInvalid
+			return
+		}
+
+		t.mu.Lock()
+		if err := t.ensureRev(ctx); err != nil {
+			t.mu.Unlock()
+			*errp = err
+			return
+		}
+		resp, err := etcdRange(ctx, t.client, t.rev, skipReservedKeyspace(beg), end, kvutil.RangeOptions{Reverse: descend})
+		if err != nil {
+			t.mu.Unlock()
+			*errp = err
+			return
+		}
+
+		type pair struct {
+			key   string
+			value []byte
+		}
+		merged := make(map[string]pair, len(resp.Kvs)+len(t.writes))
+		for _, kv := range resp.Kvs {
+			key := string(kv.Key)
+			if isReservedKey(key) {
+				continue
+			}
+			merged[key] = pair{key: key, value: kv.Value}
+			t.reads[key] = kv.ModRevision
+		}
+		for key, op := range t.writes {
+			if !inRange(key, beg, end) {
+				continue
+			}
+			if op.delete {
+				delete(merged, key)
+				continue
+			}
+			merged[key] = pair{key: key, value: op.value}
+		}
+		t.mu.Unlock()
+
+		keys := make([]string, 0, len(merged))
+		for k := range merged {
+			keys = append(keys, k)
+		}
+		if descend {
+			sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+		} else {
+			sort.Strings(keys)
+		}
+
+		for _, k := range keys {
+			if ctx.Err() != nil {
+				*errp = ctx.Err()
+				return
+			}
+			if !yield(k, bytes.NewReader(merged[k].value)) {
+				return
+			}
+		}
+	}
+}
+
+func inRange(key, beg, end string) bool {
+	if beg != "" && key < beg {
+		return false
+	}
+	if end != "" && key >= end {
+		return false
+	}
+	return true
+}
+
+// Rollback cancels the transaction without checking for conflicts.
+func (t *Transaction) Rollback(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.committed || t.rolledback {
+		return os.ErrClosed
+	}
+	t.rolledback = true
+	return nil
+}
+
+// markerLeaseTTL bounds how long a committed transaction's marker key
+// (txnMarkerPrefix+id) lives in etcd. It only needs to outlive however long
+// a client might retry Commit after losing a response, so it is generous
+// but not unbounded -- without it, every commit a long-running or
+// high-throughput service ever makes would permanently occupy a key.
+const markerLeaseTTL = 24 * 60 * 60 // seconds
+
+// Commit validates every key read by this transaction against its pinned
+// revision and, if none have changed, atomically applies the write-set in a
+// single etcd Txn. The commit marker is written in the same Txn under a
+// lease, so that if the RPC response is lost to a network error, a retry
+// can look up the marker to learn the real outcome instead of reporting a
+// false failure, and so that the marker itself does not accumulate forever.
+func (t *Transaction) Commit(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.committed {
+		return nil
+	}
+	if t.rolledback {
+		return os.ErrClosed
+	}
+
+	lease, err := t.client.Grant(ctx, markerLeaseTTL)
+	if err != nil {
+		return fmt.Errorf("etcd commit: could not grant marker lease: %w", err)
+	}
+
+	cmps := make([]clientv3.Cmp, 0, len(t.reads)+1)
+	for key, rev := range t.reads {
+		if rev == 0 {
+			cmps = append(cmps, clientv3.Compare(clientv3.CreateRevision(key), "=", 0))
+		} else {
+			cmps = append(cmps, clientv3.Compare(clientv3.ModRevision(key), "=", rev))
+		}
+	}
+	cmps = append(cmps, clientv3.Compare(clientv3.CreateRevision(txnMarkerPrefix+t.id), "=", 0))
+
+	ops := make([]clientv3.Op, 0, len(t.writes)+1)
+	for key, op := range t.writes {
+		if op.delete {
+			ops = append(ops, clientv3.OpDelete(key))
+		} else {
+			ops = append(ops, clientv3.OpPut(key, string(op.value)))
+		}
+	}
+	ops = append(ops, clientv3.OpPut(txnMarkerPrefix+t.id, "committed", clientv3.WithLease(lease.ID)))
+
+	resp, err := t.client.Txn(ctx).If(cmps...).Then(ops...).Commit()
+	if err != nil {
+		// The RPC may have actually succeeded on the server even though the
+		// client failed to observe the response (timeout, connection reset,
+		// etc). The backlog requires that this never be reported as a
+		// failure if the transaction in fact committed, so keep checking the
+		// marker -- retrying through transient errors on the check itself --
+		// until ctx says to give up.
+		return t.confirmCommitAfterError(ctx, err)
+	}
+	if !resp.Succeeded {
+		return fmt.Errorf("transaction conflict: a read key was modified concurrently")
+	}
+
+	t.committed = true
+	return nil
+}
+
+// confirmCommitAfterError is called when the Commit RPC itself returned an
+// error, to determine whether the underlying Txn nonetheless went through.
+// It retries the marker lookup (not the Txn) forever, bounded only by ctx,
+// since a transient failure on the lookup itself must not be mistaken for
+// "the transaction did not commit".
+func (t *Transaction) confirmCommitAfterError(ctx context.Context, commitErr error) error {
+	backoff := 100 * time.Millisecond
+	const maxBackoff = 5 * time.Second
+
+	for {
+		marker, err := t.client.Get(ctx, txnMarkerPrefix+t.id)
+		if err == nil {
+			if len(marker.Kvs) > 0 {
+				t.committed = true
+				return nil
+			}
+			return fmt.Errorf("etcd commit: %w", commitErr)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("etcd commit: could not confirm outcome of %w: %w", commitErr, ctx.Err())
+		case <-time.After(backoff):
+		}
+		if backoff < maxBackoff {
+			backoff *= 2
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2025 Visvasity LLC
+
+// Package kvpebble implements the kv.Database, kv.Transaction and
+// kv.Snapshot interfaces on top of a CockroachDB Pebble store, for
+// applications that want an embedded, single-process backend.
+package kvpebble
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/visvasity/kv"
+)
+
+// seqKey is the reserved metadata key holding the last sequence number
+// assigned to a committed transaction. Every stored value is prefixed with
+// the sequence number at which it was last written, so that a transaction
+// can tell -- without any extra per-key bookkeeping keys -- whether a key it
+// read has changed since it read it.
+const seqKey = "\x00kv/seq"
+
+// Database implements the kv.Database interface on top of a *pebble.DB. Use
+// NewDatabase to create one.
+type Database struct {
+	db *pebble.DB
+
+	// commitMu serializes the verify-then-apply critical section of Commit,
+	// since Pebble itself has no notion of a multi-key conditional commit.
+	commitMu sync.Mutex
+}
+
+// NewDatabase returns a kv.Database backed by the given, already-open Pebble
+// store. The store is not closed by the returned database; callers remain
+// responsible for its lifecycle.
+func NewDatabase(ctx context.Context, db *pebble.DB) (kv.Database, error) {
+	d := &Database{db: db}
+	return kv.DatabaseFrom[*Transaction, *Snapshot](d), nil
+}
+
+// NewTransaction begins a new optimistic, read-write transaction.
+func (d *Database) NewTransaction(ctx context.Context) (*Transaction, error) {
+	return newTransaction(d), nil
+}
+
+// NewSnapshot returns a read-only view of the database pinned to its current
+// state.
+func (d *Database) NewSnapshot(ctx context.Context) (*Snapshot, error) {
+	return newSnapshot(d.db.NewSnapshot()), nil
+}
+
+// isReservedKey reports whether key belongs to the internal metadata
+// keyspace (such as seqKey) and must never be surfaced through Ascend/
+// Descend scans or included in a range's read-set. Get does not check it.
+func isReservedKey(key string) bool {
+	return len(key) > 0 && key[0] == 0
+}
+
+// validRange reports whether beg/end satisfy kv.Ranger's contract: when both
+// are non-empty, beg must be less than or equal to end.
+func validRange(beg, end string) bool {
+	return beg == "" || end == "" || beg <= end
+}
+
+func readSeq(r pebble.Reader) (uint64, error) {
+	v, closer, err := r.Get([]byte(seqKey))
+	if err != nil {
+		if err == pebble.ErrNotFound {
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer closer.Close()
+	return binary.BigEndian.Uint64(v), nil
+}
+
+func encodeValue(seq uint64, value []byte) []byte {
+	out := make([]byte, 8+len(value))
+	binary.BigEndian.PutUint64(out, seq)
+	copy(out[8:], value)
+	return out
+}
+
+func decodeValue(stored []byte) (seq uint64, value []byte) {
+	return binary.BigEndian.Uint64(stored), stored[8:]
+}
@@ -0,0 +1,305 @@
+// Copyright (c) 2025 Visvasity LLC
+
+package kvpebble
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+
+	"github.com/visvasity/kv"
+	"github.com/visvasity/kv/kvutil"
+)
+
+func newTestDB(t *testing.T) kv.Database {
+	t.Helper()
+	pdb, err := pebble.Open("", &pebble.Options{FS: vfs.NewMem()})
+	if err != nil {
+		t.Fatalf("pebble.Open: %v", err)
+	}
+	t.Cleanup(func() { pdb.Close() })
+	db, err := NewDatabase(context.Background(), pdb)
+	if err != nil {
+		t.Fatalf("NewDatabase: %v", err)
+	}
+	return db
+}
+
+func set(t *testing.T, ctx context.Context, db kv.Database, key, value string) {
+	t.Helper()
+	if err := kvutil.WithReadWriter(ctx, db, func(ctx context.Context, rw kv.ReadWriter) error {
+		return rw.Set(ctx, key, strings.NewReader(value))
+	}); err != nil {
+		t.Fatalf("Set(%q): %v", key, err)
+	}
+}
+
+// TestSnapshotGetSurvivesConcurrentWrite is a regression test: Snapshot.Get
+// used to return an io.Reader aliasing memory owned by Pebble's internal
+// Closer, which Pebble is free to reuse as soon as that Closer is closed.
+// Writing a new value for an unrelated key after taking the snapshot (and
+// before the caller reads from the returned reader) must not corrupt the
+// value read from the snapshot.
+func TestSnapshotGetSurvivesConcurrentWrite(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	set(t, ctx, db, "a", "original-value")
+
+	snap, err := db.NewSnapshot(ctx)
+	if err != nil {
+		t.Fatalf("NewSnapshot: %v", err)
+	}
+	defer snap.Discard(ctx)
+
+	r, err := snap.Get(ctx, "a")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	// Force Pebble to allocate and release further internal Get buffers
+	// before we ever read from r, to shake loose any aliasing bug.
+	for i := 0; i < 64; i++ {
+		set(t, ctx, db, "b", strings.Repeat("x", 256))
+	}
+
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if string(got) != "original-value" {
+		t.Fatalf("got %q, want %q", got, "original-value")
+	}
+}
+
+// TestAscendReaderValuesSurviveIterationAdvance is a regression test:
+// rangeSeq used to yield an io.Reader wrapping the iterator's Value() bytes
+// directly, which Pebble is free to overwrite or evict once the iterator
+// advances past that position or its block cache churns. Collecting every
+// reader from a full Ascend before reading any of them (the same deferred
+// read pattern Snapshot.Get was already fixed for) must not corrupt values.
+func TestAscendReaderValuesSurviveIterationAdvance(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	const n = 500
+	want := make(map[string]string, n)
+	if err := kvutil.WithReadWriter(ctx, db, func(ctx context.Context, rw kv.ReadWriter) error {
+		for i := 0; i < n; i++ {
+			k := fmt.Sprintf("k%04d", i)
+			v := strings.Repeat(strconv.Itoa(i%10), 2000)
+			want[k] = v
+			if err := rw.Set(ctx, k, strings.NewReader(v)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	snap, err := db.NewSnapshot(ctx)
+	if err != nil {
+		t.Fatalf("NewSnapshot: %v", err)
+	}
+	defer snap.Discard(ctx)
+
+	var readErr error
+	var keys []string
+	var readers []io.Reader
+	for k, v := range snap.Ascend(ctx, "", "", &readErr) {
+		keys = append(keys, k)
+		readers = append(readers, v)
+	}
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+
+	for i, k := range keys {
+		got, err := io.ReadAll(readers[i])
+		if err != nil {
+			t.Fatalf("ReadAll(%q): %v", k, err)
+		}
+		if string(got) != want[k] {
+			t.Errorf("key %q = %d bytes not matching original value (corrupted by iterator advance?)", k, len(got))
+		}
+	}
+}
+
+func TestGetSetDelete(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	if err := kvutil.WithReadWriter(ctx, db, func(ctx context.Context, rw kv.ReadWriter) error {
+		if _, err := rw.Get(ctx, "missing"); err != os.ErrNotExist {
+			t.Errorf("Get(missing) = %v, want os.ErrNotExist", err)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	set(t, ctx, db, "k", "v1")
+
+	if err := kvutil.WithReader(ctx, db, func(ctx context.Context, r kv.Reader) error {
+		v, err := r.Get(ctx, "k")
+		if err != nil {
+			return err
+		}
+		b, _ := io.ReadAll(v)
+		if string(b) != "v1" {
+			t.Errorf("got %q, want v1", b)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := kvutil.WithReadWriter(ctx, db, func(ctx context.Context, rw kv.ReadWriter) error {
+		return rw.Delete(ctx, "k")
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := kvutil.WithReader(ctx, db, func(ctx context.Context, r kv.Reader) error {
+		if _, err := r.Get(ctx, "k"); err != os.ErrNotExist {
+			t.Errorf("Get(k) after delete = %v, want os.ErrNotExist", err)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTransactionConflictDetection(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	set(t, ctx, db, "k", "v1")
+
+	tx1, err := db.NewTransaction(ctx)
+	if err != nil {
+		t.Fatalf("NewTransaction: %v", err)
+	}
+	if _, err := tx1.Get(ctx, "k"); err != nil {
+		t.Fatalf("tx1.Get: %v", err)
+	}
+
+	// A concurrent transaction modifies the same key and commits first.
+	set(t, ctx, db, "k", "v2")
+
+	if err := tx1.Set(ctx, "k", strings.NewReader("v3")); err != nil {
+		t.Fatalf("tx1.Set: %v", err)
+	}
+	if err := tx1.Commit(ctx); err == nil {
+		t.Fatalf("tx1.Commit succeeded, want conflict error")
+	}
+}
+
+// TestAscendRejectsInvertedRange covers kv.go's Ranger contract: when beg
+// and end are both non-empty, beg must be less than or equal to end.
+func TestAscendRejectsInvertedRange(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	set(t, ctx, db, "a", "1")
+
+	if err := kvutil.WithReader(ctx, db, func(ctx context.Context, r kv.Reader) error {
+		var err error
+		var n int
+		for range r.Ascend(ctx, "z", "a", &err) {
+			n++
+		}
+		if err != os.ErrInvalid {
+			t.Errorf("Ascend(z, a) err = %v, want os.ErrInvalid", err)
+		}
+		if n != 0 {
+			t.Errorf("Ascend(z, a) yielded %d pairs, want 0", n)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := db.NewTransaction(ctx)
+	if err != nil {
+		t.Fatalf("NewTransaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+	var err2 error
+	for range tx.Ascend(ctx, "z", "a", &err2) {
+	}
+	if err2 != os.ErrInvalid {
+		t.Errorf("Transaction.Ascend(z, a) err = %v, want os.ErrInvalid", err2)
+	}
+}
+
+func TestAscendDescendEmptyRange(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	set(t, ctx, db, "a", "1")
+	set(t, ctx, db, "b", "2")
+	set(t, ctx, db, "c", "3")
+
+	if err := kvutil.WithReader(ctx, db, func(ctx context.Context, r kv.Reader) error {
+		var err error
+		var keys []string
+		for k := range r.Ascend(ctx, "", "", &err) {
+			keys = append(keys, k)
+		}
+		if err != nil {
+			return err
+		}
+		if got, want := strings.Join(keys, ","), "a,b,c"; got != want {
+			t.Errorf("Ascend keys = %q, want %q", got, want)
+		}
+
+		keys = nil
+		for k := range r.Descend(ctx, "", "", &err) {
+			keys = append(keys, k)
+		}
+		if err != nil {
+			return err
+		}
+		if got, want := strings.Join(keys, ","), "c,b,a"; got != want {
+			t.Errorf("Descend keys = %q, want %q", got, want)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRangeExtLimitAndKeysOnly(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	set(t, ctx, db, "a", "1")
+	set(t, ctx, db, "b", "2")
+	set(t, ctx, db, "c", "3")
+
+	if err := kvutil.WithReader(ctx, db, func(ctx context.Context, r kv.Reader) error {
+		var err error
+		var keys []string
+		for k, v := range kvutil.GetRange(ctx, r, "", "", kvutil.RangeOptions{Limit: 2, KeysOnly: true}, &err) {
+			keys = append(keys, k)
+			b, _ := io.ReadAll(v)
+			if len(b) != 0 {
+				t.Errorf("KeysOnly returned non-empty value %q for key %q", b, k)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if got, want := strings.Join(keys, ","), "a,b"; got != want {
+			t.Errorf("keys = %q, want %q", got, want)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2025 Visvasity LLC
+
+package kvpebble
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/visvasity/kv"
+)
+
+func init() {
+	kv.Register("pebble", openDSN)
+}
+
+// openDSN implements kv.Opener for DSNs of the form
+// "pebble:///var/lib/app/db", opening (and creating, if necessary) a Pebble
+// store at the DSN's path.
+func openDSN(ctx context.Context, u *url.URL) (kv.Database, error) {
+	if u.Path == "" {
+		return nil, fmt.Errorf("pebble dsn %q is missing a path", u.String())
+	}
+	db, err := pebble.Open(u.Path, &pebble.Options{})
+	if err != nil {
+		return nil, err
+	}
+	return NewDatabase(ctx, db)
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2025 Visvasity LLC
+
+package kvpebble
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"iter"
+	"os"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/visvasity/kv/kvutil"
+)
+
+// Snapshot implements the kv.Snapshot interface on top of Pebble's native
+// *pebble.Snapshot.
+type Snapshot struct {
+	snap *pebble.Snapshot
+}
+
+func newSnapshot(snap *pebble.Snapshot) *Snapshot {
+	return &Snapshot{snap: snap}
+}
+
+// Get returns the value for key as observed by the snapshot.
+func (s *Snapshot) Get(ctx context.Context, key string) (io.Reader, error) {
+	if key == "" {
+		return nil, os.ErrInvalid
+	}
+	stored, closer, err := s.snap.Get([]byte(key))
+	if err != nil {
+		if err == pebble.ErrNotFound {
+			return nil, os.ErrNotExist
+		}
+		return nil, err
+	}
+	defer closer.Close()
+	_, value := decodeValue(stored)
+	// value aliases memory owned by closer (Pebble reuses it once closer is
+	// closed), so it must be copied before the deferred Close runs.
+	out := append([]byte(nil), value...)
+	return bytes.NewReader(out), nil
+}
+
+// Ascend returns key-value pairs in the range in ascending order.
+func (s *Snapshot) Ascend(ctx context.Context, beg, end string, errp *error) iter.Seq2[string, io.Reader] {
+	return s.RangeExt(ctx, beg, end, kvutil.RangeOptions{}, errp)
+}
+
+// Descend is similar to Ascend but iterates in descending order.
+func (s *Snapshot) Descend(ctx context.Context, beg, end string, errp *error) iter.Seq2[string, io.Reader] {
+	return s.RangeExt(ctx, beg, end, kvutil.RangeOptions{Reverse: true}, errp)
+}
+
+// RangeExt implements kvutil.RangerExt natively on top of Pebble's own
+// bounded iterator: the range is bounded by beg/end up front via
+// LowerBound/UpperBound, Limit stops the scan early, and KeysOnly skips
+// decoding the stored value entirely.
+func (s *Snapshot) RangeExt(ctx context.Context, beg, end string, opts kvutil.RangeOptions, errp *error) iter.Seq2[string, io.Reader] {
+	return rangeSeq(ctx, s.snap, beg, end, opts, errp)
+}
+
+// Discard releases the underlying Pebble snapshot.
+func (s *Snapshot) Discard(ctx context.Context) error {
+	return s.snap.Close()
+}
+
+// iterReader is the subset of *pebble.Snapshot/*pebble.DB used to build a
+// range iterator, so the same rangeSeq helper can serve both.
+type iterReader interface {
+	NewIter(*pebble.IterOptions) (*pebble.Iterator, error)
+}
+
+var emptyReader = bytes.NewReader(nil)
+
+func rangeSeq(ctx context.Context, r iterReader, beg, end string, opts kvutil.RangeOptions, errp *error) iter.Seq2[string, io.Reader] {
+	return func(yield func(string, io.Reader) bool) {
+		if !validRange(beg, end) {
+			*errp = os.ErrInvalid
+			return
+		}
+
+		iterOpts := &pebble.IterOptions{}
+		if beg != "" {
+			iterOpts.LowerBound = []byte(beg)
+		}
+		if end != "" {
+			iterOpts.UpperBound = []byte(end)
+		}
+		it, err := r.NewIter(iterOpts)
+		if err != nil {
+			*errp = err
+			return
+		}
+		defer it.Close()
+
+		var ok bool
+		if opts.Reverse {
+			ok = it.Last()
+		} else {
+			ok = it.First()
+		}
+
+		n := 0
+		for ok {
+			if ctx.Err() != nil {
+				*errp = ctx.Err()
+				return
+			}
+			key := string(it.Key())
+			if isReservedKey(key) {
+				if opts.Reverse {
+					ok = it.Prev()
+				} else {
+					ok = it.Next()
+				}
+				continue
+			}
+			if opts.Limit > 0 && n >= opts.Limit {
+				return
+			}
+
+			var value io.Reader = emptyReader
+			if !opts.KeysOnly {
+				_, v := decodeValue(it.Value())
+				// v aliases memory Pebble is free to reuse or evict once the
+				// iterator advances past this position, so it must be copied
+				// before yielding, exactly as Get copies before its deferred
+				// Close runs.
+				value = bytes.NewReader(append([]byte(nil), v...))
+			}
+			if !yield(key, value) {
+				return
+			}
+			n++
+			if opts.Reverse {
+				ok = it.Prev()
+			} else {
+				ok = it.Next()
+			}
+		}
+		if err := it.Error(); err != nil {
+			*errp = err
+		}
+	}
+}
@@ -0,0 +1,331 @@
+// Copyright (c) 2025 Visvasity LLC
+
+package kvpebble
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+)
+
+type writeOp struct {
+	value  []byte
+	delete bool
+}
+
+type keySeq struct {
+	key string
+	seq uint64
+}
+
+type rangeRead struct {
+	beg, end string
+	snapshot []keySeq
+}
+
+// Transaction implements the kv.Transaction interface on top of Pebble. Its
+// read-set (individual keys and ranges) is tracked locally and re-verified
+// against the live store on Commit; its write-set is buffered locally and
+// applied as a single Pebble batch.
+type Transaction struct {
+	db *Database
+
+	mu     sync.Mutex
+	reads  map[string]uint64
+	ranges []rangeRead
+	writes map[string]*writeOp
+
+	done bool
+}
+
+func newTransaction(db *Database) *Transaction {
+	return &Transaction{
+		db:     db,
+		reads:  make(map[string]uint64),
+		writes: make(map[string]*writeOp),
+	}
+}
+
+// Get retrieves the value for key, preferring the transaction's own pending
+// writes over the value observed in the underlying store.
+func (t *Transaction) Get(ctx context.Context, key string) (io.Reader, error) {
+	if key == "" {
+		return nil, os.ErrInvalid
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if op, ok := t.writes[key]; ok {
+		if op.delete {
+			return nil, os.ErrNotExist
+		}
+		return bytes.NewReader(op.value), nil
+	}
+
+	stored, closer, err := t.db.db.Get([]byte(key))
+	if err != nil {
+		if err == pebble.ErrNotFound {
+			t.reads[key] = 0
+			return nil, os.ErrNotExist
+		}
+		return nil, err
+	}
+	defer closer.Close()
+	seq, value := decodeValue(stored)
+	t.reads[key] = seq
+	out := make([]byte, len(value))
+	copy(out, value)
+	return bytes.NewReader(out), nil
+}
+
+// Set buffers the key-value pair locally; it is applied to the store only on
+// Commit.
+func (t *Transaction) Set(ctx context.Context, key string, value io.Reader) error {
+	if key == "" || value == nil {
+		return os.ErrInvalid
+	}
+	b, err := io.ReadAll(value)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.writes[key] = &writeOp{value: b}
+	return nil
+}
+
+// Delete buffers removal of the key locally; it is applied to the store only
+// on Commit.
+func (t *Transaction) Delete(ctx context.Context, key string) error {
+	if key == "" {
+		return os.ErrInvalid
+	}
+	if _, err := t.Get(ctx, key); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.writes[key] = &writeOp{delete: true}
+	return nil
+}
+
+// Ascend returns key-value pairs in the range in ascending order, merging
+// the transaction's pending writes over the values observed in the
+// underlying store. The scanned key set is recorded so that Commit can
+// detect concurrent inserts, updates or deletes within the range.
+func (t *Transaction) Ascend(ctx context.Context, beg, end string, errp *error) iter.Seq2[string, io.Reader] {
+	return t.rangeSeq(ctx, beg, end, false, errp)
+}
+
+// Descend is similar to Ascend but iterates in descending order.
+func (t *Transaction) Descend(ctx context.Context, beg, end string, errp *error) iter.Seq2[string, io.Reader] {
+	return t.rangeSeq(ctx, beg, end, true, errp)
+}
+
+func (t *Transaction) rangeSeq(ctx context.Context, beg, end string, descend bool, errp *error) iter.Seq2[string, io.Reader] {
+	return func(yield func(string, io.Reader) bool) {
+		if !validRange(beg, end) {
+			*errp = os.ErrInvalid
+			return
+		}
+
+		snapshot, values, err := scanRange(t.db.db, beg, end)
+		if err != nil {
+			*errp = err
+			return
+		}
+
+		t.mu.Lock()
+		merged := make(map[string][]byte, len(values)+len(t.writes))
+		for _, ks := range snapshot {
+			merged[ks.key] = values[ks.key]
+		}
+		for key, op := range t.writes {
+			if !inRange(key, beg, end) {
+				continue
+			}
+			if op.delete {
+				delete(merged, key)
+				continue
+			}
+			merged[key] = op.value
+		}
+		t.ranges = append(t.ranges, rangeRead{beg: beg, end: end, snapshot: snapshot})
+		t.mu.Unlock()
+
+		keys := make([]string, 0, len(merged))
+		for k := range merged {
+			keys = append(keys, k)
+		}
+		if descend {
+			sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+		} else {
+			sort.Strings(keys)
+		}
+
+		for _, k := range keys {
+			if ctx.Err() != nil {
+				*errp = ctx.Err()
+				return
+			}
+			if !yield(k, bytes.NewReader(merged[k])) {
+				return
+			}
+		}
+	}
+}
+
+func inRange(key, beg, end string) bool {
+	if beg != "" && key < beg {
+		return false
+	}
+	if end != "" && key >= end {
+		return false
+	}
+	return true
+}
+
+// scanRange reads the current key/sequence pairs and values in [beg, end)
+// directly from the store, skipping reserved metadata keys.
+func scanRange(r iterReader, beg, end string) ([]keySeq, map[string][]byte, error) {
+	opts := &pebble.IterOptions{}
+	if beg != "" {
+		opts.LowerBound = []byte(beg)
+	}
+	if end != "" {
+		opts.UpperBound = []byte(end)
+	}
+	it, err := r.NewIter(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer it.Close()
+
+	var snapshot []keySeq
+	values := make(map[string][]byte)
+	for ok := it.First(); ok; ok = it.Next() {
+		key := string(it.Key())
+		if isReservedKey(key) {
+			continue
+		}
+		seq, value := decodeValue(it.Value())
+		out := make([]byte, len(value))
+		copy(out, value)
+		snapshot = append(snapshot, keySeq{key: key, seq: seq})
+		values[key] = out
+	}
+	if err := it.Error(); err != nil {
+		return nil, nil, err
+	}
+	return snapshot, values, nil
+}
+
+// Rollback cancels the transaction without checking for conflicts.
+func (t *Transaction) Rollback(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.done {
+		return os.ErrClosed
+	}
+	t.done = true
+	return nil
+}
+
+// Commit verifies that every key and range read by this transaction is
+// unchanged since the transaction read it, then atomically applies the
+// write-set as a single Pebble batch tagged with a freshly bumped sequence
+// number. Writes committed after the transaction began but before a key or
+// range was read are not treated as conflicts.
+func (t *Transaction) Commit(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.done {
+		return os.ErrClosed
+	}
+
+	t.db.commitMu.Lock()
+	defer t.db.commitMu.Unlock()
+
+	for key, seq := range t.reads {
+		cur, closer, err := t.db.db.Get([]byte(key))
+		if err != nil {
+			if err == pebble.ErrNotFound {
+				if seq != 0 {
+					return fmt.Errorf("transaction conflict: key %q was deleted concurrently", key)
+				}
+				continue
+			}
+			return err
+		}
+		curSeq, _ := decodeValue(cur)
+		closer.Close()
+		if curSeq != seq {
+			return fmt.Errorf("transaction conflict: key %q was modified concurrently", key)
+		}
+	}
+
+	for _, rr := range t.ranges {
+		cur, _, err := scanRange(t.db.db, rr.beg, rr.end)
+		if err != nil {
+			return err
+		}
+		if !equalKeySeqs(rr.snapshot, cur) {
+			return fmt.Errorf("transaction conflict: range [%q, %q) was modified concurrently", rr.beg, rr.end)
+		}
+	}
+
+	seq, err := readSeq(t.db.db)
+	if err != nil {
+		return err
+	}
+	newSeq := seq + 1
+
+	batch := t.db.db.NewBatch()
+	defer batch.Close()
+	for key, op := range t.writes {
+		if op.delete {
+			if err := batch.Delete([]byte(key), nil); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := batch.Set([]byte(key), encodeValue(newSeq, op.value), nil); err != nil {
+			return err
+		}
+	}
+	var seqBuf [8]byte
+	binary.BigEndian.PutUint64(seqBuf[:], newSeq)
+	if err := batch.Set([]byte(seqKey), seqBuf[:], nil); err != nil {
+		return err
+	}
+	if err := batch.Commit(pebble.Sync); err != nil {
+		return err
+	}
+
+	t.done = true
+	return nil
+}
+
+func equalKeySeqs(a, b []keySeq) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
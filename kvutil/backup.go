@@ -0,0 +1,431 @@
+// Copyright (c) 2025 Visvasity LLC
+
+package kvutil
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/visvasity/kv"
+)
+
+// backupMagic identifies the start of a backup stream produced by Backup or
+// IncrementalBackup. backupVersion allows the framing below to evolve
+// without breaking readers of older backups.
+var backupMagic = [4]byte{'K', 'V', 'B', 'K'}
+
+const backupVersion = 1
+
+// Manifest describes a backup stream. It is always written and read
+// uncompressed, so that Restore and ValidateBackup can learn the Codec
+// before they need to decompress anything.
+type Manifest struct {
+	// SnapshotID is the caller-supplied BackupOptions.SnapshotID labelling
+	// the snapshot this backup was taken from. Backup and IncrementalBackup
+	// do not generate one; it is empty unless the caller sets it.
+	SnapshotID string
+	// Timestamp is the caller-supplied BackupOptions.Timestamp, in Unix
+	// seconds. Backup and IncrementalBackup do not fill it in; it is zero
+	// unless the caller sets it.
+	Timestamp int64
+	// SourceFingerprint is an optional, caller-supplied label identifying
+	// the source database (e.g. its DSN or cluster name).
+	SourceFingerprint string
+	Codec             Codec
+	ChecksumAlgo      ChecksumAlgo
+}
+
+// BackupOptions controls how Backup and IncrementalBackup encode a stream.
+// A nil *BackupOptions is equivalent to the zero value, which selects
+// CodecNone and ChecksumCRC64ISO.
+type BackupOptions struct {
+	SnapshotID        string
+	Timestamp         int64
+	SourceFingerprint string
+	Codec             Codec
+	ChecksumAlgo      ChecksumAlgo
+}
+
+func (o *BackupOptions) orDefaults() *BackupOptions {
+	if o == nil {
+		return &BackupOptions{}
+	}
+	return o
+}
+
+// Backup saves database content into the writer as a versioned, self
+// describing stream. Written data will be a consistent snapshot of the
+// database.
+func Backup(ctx context.Context, db kv.Database, w io.Writer, opts *BackupOptions) error {
+	snap, err := db.NewSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+	defer snap.Discard(ctx)
+
+	_, err = writeBackupStream(ctx, snap, "", w, opts)
+	return err
+}
+
+// IncrementalBackup writes only the keys greater than or equal to cursor,
+// using the same versioned stream format as Backup. It returns the cursor to
+// pass to the next call to continue where this one left off; the returned
+// cursor is already adjusted to exclude the last key written, so chaining
+// calls back to back never re-emits that key.
+func IncrementalBackup(ctx context.Context, db kv.Database, w io.Writer, cursor string, opts *BackupOptions) (nextCursor string, err error) {
+	snap, err := db.NewSnapshot(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer snap.Discard(ctx)
+
+	lastKey, err := writeBackupStream(ctx, snap, cursor, w, opts)
+	if err != nil {
+		return "", err
+	}
+	if lastKey == "" {
+		// Nothing was written; resume from the same cursor next time.
+		return cursor, nil
+	}
+	// Ascend's beg is inclusive (see kv.go), so feeding lastKey back as-is
+	// would re-emit it on the next call. Appending a 0x00 byte produces the
+	// lexicographically smallest string strictly greater than lastKey.
+	return lastKey + "\x00", nil
+}
+
+func writeBackupStream(ctx context.Context, r kv.Reader, beg string, w io.Writer, opts *BackupOptions) (lastKey string, err error) {
+	opts = opts.orDefaults()
+
+	if _, err := w.Write(backupMagic[:]); err != nil {
+		return "", err
+	}
+	if err := writeUvarint(w, backupVersion); err != nil {
+		return "", err
+	}
+
+	m := &Manifest{
+		SnapshotID:        opts.SnapshotID,
+		Timestamp:         opts.Timestamp,
+		SourceFingerprint: opts.SourceFingerprint,
+		Codec:             opts.Codec,
+		ChecksumAlgo:      opts.ChecksumAlgo,
+	}
+	var mbuf bytes.Buffer
+	if err := gob.NewEncoder(&mbuf).Encode(m); err != nil {
+		return "", err
+	}
+	if err := writeUvarint(w, uint64(mbuf.Len())); err != nil {
+		return "", err
+	}
+	if _, err := w.Write(mbuf.Bytes()); err != nil {
+		return "", err
+	}
+
+	body, err := wrapWriter(opts.Codec, w)
+	if err != nil {
+		return "", err
+	}
+
+	trailerHash, err := newHash(opts.ChecksumAlgo)
+	if err != nil {
+		return "", err
+	}
+
+	var count int64
+	var readErr error
+	for k, v := range r.Ascend(ctx, beg, "", &readErr) {
+		value, err := io.ReadAll(v)
+		if err != nil {
+			return "", err
+		}
+		csum, err := checksum(opts.ChecksumAlgo, k, value)
+		if err != nil {
+			return "", err
+		}
+		if err := writeRecord(body, k, value, csum); err != nil {
+			return "", err
+		}
+		if _, err := trailerHash.Write(csum); err != nil {
+			return "", err
+		}
+		count++
+		lastKey = k
+	}
+	if readErr != nil {
+		return "", readErr
+	}
+
+	// End-of-records sentinel: keys are never empty, so a zero key length is
+	// unambiguous.
+	if err := writeUvarint(body, 0); err != nil {
+		return "", err
+	}
+	if err := writeUvarint(body, uint64(count)); err != nil {
+		return "", err
+	}
+	trailerSum := trailerHash.Sum(nil)
+	if err := writeUvarint(body, uint64(len(trailerSum))); err != nil {
+		return "", err
+	}
+	if _, err := body.Write(trailerSum); err != nil {
+		return "", err
+	}
+
+	if err := body.Close(); err != nil {
+		return "", err
+	}
+	return lastKey, nil
+}
+
+func writeRecord(w io.Writer, key string, value, csum []byte) error {
+	if err := writeUvarint(w, uint64(len(key))); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, key); err != nil {
+		return err
+	}
+	if err := writeUvarint(w, uint64(len(value))); err != nil {
+		return err
+	}
+	if _, err := w.Write(value); err != nil {
+		return err
+	}
+	if err := writeUvarint(w, uint64(len(csum))); err != nil {
+		return err
+	}
+	if _, err := w.Write(csum); err != nil {
+		return err
+	}
+	return nil
+}
+
+func writeUvarint(w io.Writer, x uint64) error {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], x)
+	_, err := w.Write(buf[:n])
+	return err
+}
+
+// backupReader streams records out of a backup stream written by
+// writeBackupStream, verifying each record's checksum as it goes and
+// accumulating the state needed to verify the trailer once the records are
+// exhausted.
+type backupReader struct {
+	body  *bufio.Reader
+	algo  ChecksumAlgo
+	hash  hash.Hash
+	count int64
+}
+
+func openBackupStream(r io.Reader) (*Manifest, *backupReader, error) {
+	br := bufio.NewReader(r)
+
+	var magic [4]byte
+	if _, err := io.ReadFull(br, magic[:]); err != nil {
+		return nil, nil, fmt.Errorf("could not read backup magic: %w", err)
+	}
+	if magic != backupMagic {
+		return nil, nil, fmt.Errorf("not a kv backup stream (bad magic %q)", magic)
+	}
+	version, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read backup version: %w", err)
+	}
+	if version != backupVersion {
+		return nil, nil, fmt.Errorf("unsupported backup version %d", version)
+	}
+
+	mlen, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read manifest length: %w", err)
+	}
+	mbuf := make([]byte, mlen)
+	if _, err := io.ReadFull(br, mbuf); err != nil {
+		return nil, nil, fmt.Errorf("could not read manifest: %w", err)
+	}
+	var m Manifest
+	if err := gob.NewDecoder(bytes.NewReader(mbuf)).Decode(&m); err != nil {
+		return nil, nil, fmt.Errorf("could not decode manifest: %w", err)
+	}
+
+	body, err := wrapReader(m.Codec, br)
+	if err != nil {
+		return nil, nil, err
+	}
+	h, err := newHash(m.ChecksumAlgo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &m, &backupReader{body: bufio.NewReader(body), algo: m.ChecksumAlgo, hash: h}, nil
+}
+
+// next returns the next record in the stream. ok is false once the
+// end-of-records sentinel is reached, at which point the caller should call
+// trailer to validate the stream's trailer.
+func (br *backupReader) next() (key string, value []byte, ok bool, err error) {
+	keyLen, err := binary.ReadUvarint(br.body)
+	if err != nil {
+		return "", nil, false, fmt.Errorf("could not read record: %w", err)
+	}
+	if keyLen == 0 {
+		return "", nil, false, nil
+	}
+
+	keyBuf := make([]byte, keyLen)
+	if _, err := io.ReadFull(br.body, keyBuf); err != nil {
+		return "", nil, false, err
+	}
+	valLen, err := binary.ReadUvarint(br.body)
+	if err != nil {
+		return "", nil, false, err
+	}
+	valBuf := make([]byte, valLen)
+	if _, err := io.ReadFull(br.body, valBuf); err != nil {
+		return "", nil, false, err
+	}
+	csumLen, err := binary.ReadUvarint(br.body)
+	if err != nil {
+		return "", nil, false, err
+	}
+	csumBuf := make([]byte, csumLen)
+	if _, err := io.ReadFull(br.body, csumBuf); err != nil {
+		return "", nil, false, err
+	}
+
+	want, err := checksum(br.algo, string(keyBuf), valBuf)
+	if err != nil {
+		return "", nil, false, err
+	}
+	if !bytes.Equal(want, csumBuf) {
+		return "", nil, false, fmt.Errorf("checksum error detected for key %q", keyBuf)
+	}
+
+	br.count++
+	if _, err := br.hash.Write(csumBuf); err != nil {
+		return "", nil, false, err
+	}
+	return string(keyBuf), valBuf, true, nil
+}
+
+// trailer reads and validates the trailer following the end-of-records
+// sentinel: the total record count and the checksum-of-checksums.
+func (br *backupReader) trailer() error {
+	count, err := binary.ReadUvarint(br.body)
+	if err != nil {
+		return fmt.Errorf("could not read trailer record count: %w", err)
+	}
+	if int64(count) != br.count {
+		return fmt.Errorf("trailer record count %d does not match %d records read", count, br.count)
+	}
+	sumLen, err := binary.ReadUvarint(br.body)
+	if err != nil {
+		return fmt.Errorf("could not read trailer checksum length: %w", err)
+	}
+	sumBuf := make([]byte, sumLen)
+	if _, err := io.ReadFull(br.body, sumBuf); err != nil {
+		return err
+	}
+	if !bytes.Equal(sumBuf, br.hash.Sum(nil)) {
+		return fmt.Errorf("backup trailer checksum-of-checksums mismatch")
+	}
+	return nil
+}
+
+// ValidateBackup scans a backup stream for checksum errors, including the
+// trailer's checksum-of-checksums.
+func ValidateBackup(ctx context.Context, r io.Reader) error {
+	_, br, err := openBackupStream(r)
+	if err != nil {
+		return err
+	}
+	for {
+		_, _, ok, err := br.next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+	}
+	return br.trailer()
+}
+
+// RestoreOptions controls how Restore resumes a partially applied backup.
+type RestoreOptions struct {
+	// ResumeAfterKey skips every record up to and including this key. Pass
+	// the last key successfully applied by a previous, interrupted Restore
+	// call to continue without redoing already-applied ranges.
+	ResumeAfterKey string
+}
+
+// Restore updates the database with key-value content from a backup stream
+// written by Backup or IncrementalBackup. When maxPerTx is non-zero, restore
+// happens in multiple transactions with at most maxPerTx updates in one
+// transaction.
+func Restore(ctx context.Context, db kv.Database, r io.Reader, maxPerTx int64, opts *RestoreOptions) error {
+	if maxPerTx < 0 {
+		return fmt.Errorf("maxPerTx must not be negative")
+	}
+	if maxPerTx == 0 {
+		maxPerTx = 1 << 62
+	}
+	if opts == nil {
+		opts = &RestoreOptions{}
+	}
+
+	_, br, err := openBackupStream(r)
+	if err != nil {
+		return err
+	}
+
+	skipping := opts.ResumeAfterKey != ""
+	done := false
+	for !done {
+		err := func() error {
+			tx, err := db.NewTransaction(ctx)
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(ctx)
+
+			n := int64(0)
+			for n < maxPerTx {
+				key, value, ok, err := br.next()
+				if err != nil {
+					return err
+				}
+				if !ok {
+					done = true
+					break
+				}
+				if skipping {
+					if key <= opts.ResumeAfterKey {
+						continue
+					}
+					skipping = false
+				}
+				if err := tx.Set(ctx, key, bytes.NewReader(value)); err != nil {
+					return err
+				}
+				n++
+			}
+
+			if err := tx.Commit(ctx); err != nil {
+				return err
+			}
+			return nil
+		}()
+		if err != nil {
+			return err
+		}
+	}
+
+	return br.trailer()
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2025 Visvasity LLC
+
+package kvutil
+
+import (
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"hash/crc64"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// ChecksumAlgo identifies the hash used to checksum individual backup
+// records and the backup trailer. It is recorded in the manifest so that
+// Restore and ValidateBackup never have to guess.
+type ChecksumAlgo uint8
+
+const (
+	// ChecksumCRC64ISO is the default algorithm, matching the checksum the
+	// original gob-based backup format used.
+	ChecksumCRC64ISO ChecksumAlgo = iota
+	ChecksumCRC32C
+	ChecksumXXHash64
+)
+
+func (a ChecksumAlgo) String() string {
+	switch a {
+	case ChecksumCRC64ISO:
+		return "crc64-iso"
+	case ChecksumCRC32C:
+		return "crc32c"
+	case ChecksumXXHash64:
+		return "xxhash64"
+	default:
+		return fmt.Sprintf("ChecksumAlgo(%d)", uint8(a))
+	}
+}
+
+func newHash(algo ChecksumAlgo) (hash.Hash, error) {
+	switch algo {
+	case ChecksumCRC64ISO:
+		return crc64.New(crc64.MakeTable(crc64.ISO)), nil
+	case ChecksumCRC32C:
+		return crc32.New(crc32.MakeTable(crc32.Castagnoli)), nil
+	case ChecksumXXHash64:
+		return xxhash.New(), nil
+	default:
+		return nil, fmt.Errorf("unsupported checksum algorithm %d", algo)
+	}
+}
+
+// checksum returns the checksum of key and value under algo.
+func checksum(algo ChecksumAlgo, key string, value []byte) ([]byte, error) {
+	h, err := newHash(algo)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := h.Write([]byte(key)); err != nil {
+		return nil, err
+	}
+	if _, err := h.Write(value); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2025 Visvasity LLC
+
+package kvutil
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/golang/snappy"
+)
+
+// Codec identifies the compression applied to the record stream following
+// the manifest. The manifest itself is always written uncompressed so that
+// a reader can learn the codec before it needs to decompress anything.
+type Codec uint8
+
+const (
+	CodecNone Codec = iota
+	CodecSnappy
+)
+
+func (c Codec) String() string {
+	switch c {
+	case CodecNone:
+		return "none"
+	case CodecSnappy:
+		return "snappy"
+	default:
+		return fmt.Sprintf("Codec(%d)", uint8(c))
+	}
+}
+
+// wrapWriter returns a writer that compresses everything written to it
+// according to codec. Callers must Close the returned writer (via
+// io.Closer, when applicable) to flush any buffered output.
+func wrapWriter(codec Codec, w io.Writer) (io.WriteCloser, error) {
+	switch codec {
+	case CodecNone:
+		return nopWriteCloser{w}, nil
+	case CodecSnappy:
+		return snappy.NewBufferedWriter(w), nil
+	default:
+		return nil, fmt.Errorf("unsupported backup codec %d", codec)
+	}
+}
+
+// wrapReader returns a reader that decompresses r according to codec.
+func wrapReader(codec Codec, r io.Reader) (io.Reader, error) {
+	switch codec {
+	case CodecNone:
+		return r, nil
+	case CodecSnappy:
+		return snappy.NewReader(r), nil
+	default:
+		return nil, fmt.Errorf("unsupported backup codec %d", codec)
+	}
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
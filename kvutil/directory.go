@@ -0,0 +1,143 @@
+// Copyright (c) 2025 Visvasity LLC
+
+package kvutil
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/visvasity/kv"
+)
+
+// dirMetaPrefix is the reserved metadata prefix a Directory uses to record
+// the name-to-id mapping for the subspaces it manages. dirDataPrefix is the
+// reserved prefix under which those subspaces actually live, keyed by their
+// short, allocated id rather than their (possibly long) name, so that
+// on-disk keys stay compact. Neither prefix begins with 0x00, which the
+// kvetcd and kvpebble backends reserve for their own metadata and leave out
+// of every range scan.
+const (
+	dirMetaPrefix = "\x02dir/name/"
+	dirNextIDKey  = "\x02dir/nextid"
+	dirDataPrefix = "\x01"
+)
+
+// Directory manages a registry of named subspaces of a kv.Transaction,
+// analogous to FoundationDB's directory layer. Each name is allocated a
+// short byte id the first time it is created, so that referencing a
+// subspace repeatedly does not repeat its full name on disk.
+type Directory struct {
+	tx kv.Transaction
+}
+
+// NewDirectory returns a Directory that manages subspaces of tx. All
+// operations on the returned Directory take effect only when tx is
+// committed.
+func NewDirectory(tx kv.Transaction) *Directory {
+	return &Directory{tx: tx}
+}
+
+func (d *Directory) nameKey(name string) string {
+	return dirMetaPrefix + name
+}
+
+func (d *Directory) allocID(ctx context.Context) ([]byte, error) {
+	var next uint64
+	if err := GetGob(ctx, d.tx, dirNextIDKey, &next); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		next = 0
+	}
+
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], next)
+	id := append([]byte(nil), buf[:n]...)
+
+	if err := SetGob(ctx, d.tx, dirNextIDKey, next+1); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+// CreateOrOpen returns the subspace for name, allocating it if it does not
+// already exist.
+func (d *Directory) CreateOrOpen(ctx context.Context, name string) (kv.ReadWriter, error) {
+	if name == "" {
+		return nil, os.ErrInvalid
+	}
+
+	var id []byte
+	if err := GetGob(ctx, d.tx, d.nameKey(name), &id); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		newID, err := d.allocID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := SetGob(ctx, d.tx, d.nameKey(name), newID); err != nil {
+			return nil, err
+		}
+		id = newID
+	}
+	return NamespaceReadWriter(d.tx, dirDataPrefix+string(id)), nil
+}
+
+// Open returns the subspace for name, or os.ErrNotExist if it has not been
+// created.
+func (d *Directory) Open(ctx context.Context, name string) (kv.ReadWriter, error) {
+	if name == "" {
+		return nil, os.ErrInvalid
+	}
+	var id []byte
+	if err := GetGob(ctx, d.tx, d.nameKey(name), &id); err != nil {
+		return nil, err
+	}
+	return NamespaceReadWriter(d.tx, dirDataPrefix+string(id)), nil
+}
+
+// List returns the names of every subspace created so far.
+func (d *Directory) List(ctx context.Context) ([]string, error) {
+	beg, end := PrefixRange(dirMetaPrefix)
+	var names []string
+	var err error
+	for k := range d.tx.Ascend(ctx, beg, end, &err) {
+		names = append(names, strings.TrimPrefix(k, dirMetaPrefix))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// Remove deletes the subspace for name, including its name-to-id mapping
+// and all key-value pairs stored under it.
+func (d *Directory) Remove(ctx context.Context, name string) error {
+	if name == "" {
+		return os.ErrInvalid
+	}
+	var id []byte
+	if err := GetGob(ctx, d.tx, d.nameKey(name), &id); err != nil {
+		return err
+	}
+
+	beg, end := PrefixRange(dirDataPrefix + string(id))
+	var keys []string
+	var err error
+	for k := range d.tx.Ascend(ctx, beg, end, &err) {
+		keys = append(keys, k)
+	}
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := d.tx.Delete(ctx, k); err != nil {
+			return err
+		}
+	}
+	return d.tx.Delete(ctx, d.nameKey(name))
+}
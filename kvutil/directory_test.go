@@ -0,0 +1,115 @@
+// Copyright (c) 2025 Visvasity LLC
+
+package kvutil_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/visvasity/kv/kvutil"
+)
+
+func TestDirectoryCreateOrOpenAllocatesOnce(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	tx, err := db.NewTransaction(ctx)
+	if err != nil {
+		t.Fatalf("NewTransaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	dir := kvutil.NewDirectory(tx)
+
+	sub1, err := dir.CreateOrOpen(ctx, "widgets")
+	if err != nil {
+		t.Fatalf("CreateOrOpen: %v", err)
+	}
+	if err := sub1.Set(ctx, "k", strings.NewReader("v")); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	// A second CreateOrOpen for the same name must resolve to the same
+	// subspace rather than allocating a new id.
+	sub2, err := dir.CreateOrOpen(ctx, "widgets")
+	if err != nil {
+		t.Fatalf("CreateOrOpen (again): %v", err)
+	}
+	v, err := sub2.Get(ctx, "k")
+	if err != nil {
+		t.Fatalf("Get via reopened subspace: %v", err)
+	}
+	b, _ := io.ReadAll(v)
+	if string(b) != "v" {
+		t.Errorf("got %q, want %q", b, "v")
+	}
+}
+
+func TestDirectoryOpenMissingFails(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	tx, err := db.NewTransaction(ctx)
+	if err != nil {
+		t.Fatalf("NewTransaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	dir := kvutil.NewDirectory(tx)
+	if _, err := dir.Open(ctx, "missing"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Open(missing) = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestDirectoryListAndRemove(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	tx, err := db.NewTransaction(ctx)
+	if err != nil {
+		t.Fatalf("NewTransaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	dir := kvutil.NewDirectory(tx)
+	for _, name := range []string{"a", "b", "c"} {
+		sub, err := dir.CreateOrOpen(ctx, name)
+		if err != nil {
+			t.Fatalf("CreateOrOpen(%q): %v", name, err)
+		}
+		if err := sub.Set(ctx, "k", strings.NewReader(name)); err != nil {
+			t.Fatalf("Set(%q): %v", name, err)
+		}
+	}
+
+	names, err := dir.List(ctx)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	sort.Strings(names)
+	if got, want := strings.Join(names, ","), "a,b,c"; got != want {
+		t.Errorf("List = %q, want %q", got, want)
+	}
+
+	if err := dir.Remove(ctx, "b"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+
+	names, err = dir.List(ctx)
+	if err != nil {
+		t.Fatalf("List after Remove: %v", err)
+	}
+	sort.Strings(names)
+	if got, want := strings.Join(names, ","), "a,c"; got != want {
+		t.Errorf("List after Remove = %q, want %q", got, want)
+	}
+
+	if _, err := dir.Open(ctx, "b"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Open(b) after Remove = %v, want os.ErrNotExist", err)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2025 Visvasity LLC
+
+package kvutil
+
+import (
+	"context"
+	"io"
+	"iter"
+	"os"
+	"strings"
+
+	"github.com/visvasity/kv"
+)
+
+// nsCommon holds the absolute key prefix shared by every namespace view
+// below and the key translation logic they all need. The prefix always ends
+// in "/", so namespaces never see each other's keys and nest cleanly: a
+// namespace created on top of an already-namespaced view simply adds its own
+// prefix in front of the keys it delegates.
+type nsCommon struct {
+	prefix string
+}
+
+func newNSCommon(prefix string) nsCommon {
+	return nsCommon{prefix: prefix + "/"}
+}
+
+func (n *nsCommon) abs(key string) string {
+	return n.prefix + key
+}
+
+func (n *nsCommon) absBeg(beg string) string {
+	return n.prefix + beg
+}
+
+func (n *nsCommon) absEnd(end string) string {
+	if end == "" {
+		return prefixEnd(n.prefix)
+	}
+	return n.prefix + end
+}
+
+func (n *nsCommon) rel(key string) string {
+	return strings.TrimPrefix(key, n.prefix)
+}
+
+// Namespace returns a view of db where every key is transparently prefixed
+// with prefix+"/". It is analogous to FoundationDB's directory/subspace
+// layer: multiple subsystems can share one backend without hand-rolling
+// prefix arithmetic.
+func Namespace(db kv.Database, prefix string) kv.Database {
+	return &nsDatabase{nsCommon: newNSCommon(prefix), db: db}
+}
+
+// NamespaceReader is like Namespace but wraps a kv.Reader.
+func NamespaceReader(r kv.Reader, prefix string) kv.Reader {
+	return &nsReader{nsCommon: newNSCommon(prefix), r: r}
+}
+
+// NamespaceReadWriter is like Namespace but wraps a kv.ReadWriter.
+func NamespaceReadWriter(rw kv.ReadWriter, prefix string) kv.ReadWriter {
+	return &nsReadWriter{nsCommon: newNSCommon(prefix), rw: rw}
+}
+
+type nsReader struct {
+	nsCommon
+	r kv.Reader
+}
+
+func (v *nsReader) Get(ctx context.Context, key string) (io.Reader, error) {
+	if key == "" {
+		return nil, os.ErrInvalid
+	}
+	return v.r.Get(ctx, v.abs(key))
+}
+
+func (v *nsReader) Ascend(ctx context.Context, beg, end string, errp *error) iter.Seq2[string, io.Reader] {
+	return func(yield func(string, io.Reader) bool) {
+		for k, val := range v.r.Ascend(ctx, v.absBeg(beg), v.absEnd(end), errp) {
+			if !yield(v.rel(k), val) {
+				return
+			}
+		}
+	}
+}
+
+func (v *nsReader) Descend(ctx context.Context, beg, end string, errp *error) iter.Seq2[string, io.Reader] {
+	return func(yield func(string, io.Reader) bool) {
+		for k, val := range v.r.Descend(ctx, v.absBeg(beg), v.absEnd(end), errp) {
+			if !yield(v.rel(k), val) {
+				return
+			}
+		}
+	}
+}
+
+// RangeExt implements RangerExt by translating beg/end to absolute keys and
+// delegating to GetRange on the wrapped reader, so a RangerExt-capable
+// backend's native pagination (e.g. kvetcd's Limit/KeysOnly) is preserved
+// through the namespace instead of silently falling back to the portable
+// default.
+func (v *nsReader) RangeExt(ctx context.Context, beg, end string, opts RangeOptions, errp *error) iter.Seq2[string, io.Reader] {
+	return func(yield func(string, io.Reader) bool) {
+		for k, val := range GetRange(ctx, v.r, v.absBeg(beg), v.absEnd(end), opts, errp) {
+			if !yield(v.rel(k), val) {
+				return
+			}
+		}
+	}
+}
+
+type nsReadWriter struct {
+	nsCommon
+	rw kv.ReadWriter
+}
+
+func (v *nsReadWriter) Get(ctx context.Context, key string) (io.Reader, error) {
+	if key == "" {
+		return nil, os.ErrInvalid
+	}
+	return v.rw.Get(ctx, v.abs(key))
+}
+
+func (v *nsReadWriter) Set(ctx context.Context, key string, value io.Reader) error {
+	if key == "" || value == nil {
+		return os.ErrInvalid
+	}
+	return v.rw.Set(ctx, v.abs(key), value)
+}
+
+func (v *nsReadWriter) Delete(ctx context.Context, key string) error {
+	if key == "" {
+		return os.ErrInvalid
+	}
+	return v.rw.Delete(ctx, v.abs(key))
+}
+
+func (v *nsReadWriter) Ascend(ctx context.Context, beg, end string, errp *error) iter.Seq2[string, io.Reader] {
+	return func(yield func(string, io.Reader) bool) {
+		for k, val := range v.rw.Ascend(ctx, v.absBeg(beg), v.absEnd(end), errp) {
+			if !yield(v.rel(k), val) {
+				return
+			}
+		}
+	}
+}
+
+func (v *nsReadWriter) Descend(ctx context.Context, beg, end string, errp *error) iter.Seq2[string, io.Reader] {
+	return func(yield func(string, io.Reader) bool) {
+		for k, val := range v.rw.Descend(ctx, v.absBeg(beg), v.absEnd(end), errp) {
+			if !yield(v.rel(k), val) {
+				return
+			}
+		}
+	}
+}
+
+// RangeExt implements RangerExt the same way nsReader.RangeExt does, so a
+// RangerExt-capable transaction keeps its native pagination through the
+// namespace.
+func (v *nsReadWriter) RangeExt(ctx context.Context, beg, end string, opts RangeOptions, errp *error) iter.Seq2[string, io.Reader] {
+	return func(yield func(string, io.Reader) bool) {
+		for k, val := range GetRange(ctx, v.rw, v.absBeg(beg), v.absEnd(end), opts, errp) {
+			if !yield(v.rel(k), val) {
+				return
+			}
+		}
+	}
+}
+
+type nsDatabase struct {
+	nsCommon
+	db kv.Database
+}
+
+func (d *nsDatabase) NewTransaction(ctx context.Context) (kv.Transaction, error) {
+	tx, err := d.db.NewTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &nsTransaction{nsReadWriter: nsReadWriter{nsCommon: d.nsCommon, rw: tx}, tx: tx}, nil
+}
+
+func (d *nsDatabase) NewSnapshot(ctx context.Context) (kv.Snapshot, error) {
+	snap, err := d.db.NewSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &nsSnapshot{nsReader: nsReader{nsCommon: d.nsCommon, r: snap}, snap: snap}, nil
+}
+
+type nsTransaction struct {
+	nsReadWriter
+	tx kv.Transaction
+}
+
+func (t *nsTransaction) Rollback(ctx context.Context) error {
+	return t.tx.Rollback(ctx)
+}
+
+func (t *nsTransaction) Commit(ctx context.Context) error {
+	return t.tx.Commit(ctx)
+}
+
+type nsSnapshot struct {
+	nsReader
+	snap kv.Snapshot
+}
+
+func (s *nsSnapshot) Discard(ctx context.Context) error {
+	return s.snap.Discard(ctx)
+}
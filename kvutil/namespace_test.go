@@ -0,0 +1,169 @@
+// Copyright (c) 2025 Visvasity LLC
+
+package kvutil_test
+
+import (
+	"context"
+	"io"
+	"iter"
+	"strings"
+	"testing"
+
+	"github.com/visvasity/kv"
+	"github.com/visvasity/kv/kvutil"
+)
+
+// trackingReader wraps a kv.Reader and implements kvutil.RangerExt itself,
+// recording whether RangeExt was invoked. It is used to prove that wrapping
+// a RangerExt-capable value in a Namespace preserves native dispatch instead
+// of silently falling back to the portable default built on Ascend/Descend.
+type trackingReader struct {
+	kv.Reader
+	calls int
+}
+
+func (t *trackingReader) RangeExt(ctx context.Context, beg, end string, opts kvutil.RangeOptions, errp *error) iter.Seq2[string, io.Reader] {
+	t.calls++
+	if opts.Reverse {
+		return t.Descend(ctx, beg, end, errp)
+	}
+	return t.Ascend(ctx, beg, end, errp)
+}
+
+func TestNamespaceStripsPrefix(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	ns := kvutil.Namespace(db, "app")
+
+	if err := kvutil.WithReadWriter(ctx, ns, func(ctx context.Context, rw kv.ReadWriter) error {
+		return rw.Set(ctx, "k", strings.NewReader("v"))
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The underlying database must see the absolute, prefixed key.
+	if err := kvutil.WithReader(ctx, db, func(ctx context.Context, r kv.Reader) error {
+		v, err := r.Get(ctx, "app/k")
+		if err != nil {
+			return err
+		}
+		b, _ := io.ReadAll(v)
+		if string(b) != "v" {
+			t.Errorf("db.Get(app/k) = %q, want %q", b, "v")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The namespace view itself must see the relative key.
+	if err := kvutil.WithReader(ctx, ns, func(ctx context.Context, r kv.Reader) error {
+		v, err := r.Get(ctx, "k")
+		if err != nil {
+			return err
+		}
+		b, _ := io.ReadAll(v)
+		if string(b) != "v" {
+			t.Errorf("ns.Get(k) = %q, want %q", b, "v")
+		}
+
+		var err2 error
+		var keys []string
+		for kk := range r.Ascend(ctx, "", "", &err2) {
+			keys = append(keys, kk)
+		}
+		if err2 != nil {
+			return err2
+		}
+		if got, want := strings.Join(keys, ","), "k"; got != want {
+			t.Errorf("ns.Ascend keys = %q, want %q", got, want)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNamespaceNesting(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	outer := kvutil.Namespace(db, "a")
+	inner := kvutil.Namespace(outer, "b")
+
+	if err := kvutil.WithReadWriter(ctx, inner, func(ctx context.Context, rw kv.ReadWriter) error {
+		return rw.Set(ctx, "k", strings.NewReader("v"))
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := kvutil.WithReader(ctx, db, func(ctx context.Context, r kv.Reader) error {
+		v, err := r.Get(ctx, "a/b/k")
+		if err != nil {
+			return err
+		}
+		b, _ := io.ReadAll(v)
+		if string(b) != "v" {
+			t.Errorf("db.Get(a/b/k) = %q, want %q", b, "v")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNamespaceRangeExtDispatchesNatively(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	if err := kvutil.WithReadWriter(ctx, db, func(ctx context.Context, rw kv.ReadWriter) error {
+		return rw.Set(ctx, "app/k", strings.NewReader("v"))
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := kvutil.WithReader(ctx, db, func(ctx context.Context, r kv.Reader) error {
+		tr := &trackingReader{Reader: r}
+		ns := kvutil.NamespaceReader(tr, "app")
+
+		var err error
+		var keys []string
+		for k, v := range kvutil.GetRange(ctx, ns, "", "", kvutil.RangeOptions{}, &err) {
+			b, _ := io.ReadAll(v)
+			keys = append(keys, k+"="+string(b))
+		}
+		if err != nil {
+			return err
+		}
+		if tr.calls == 0 {
+			t.Error("GetRange on a namespaced RangerExt-capable reader fell back to the portable default instead of dispatching to RangeExt")
+		}
+		if got, want := strings.Join(keys, ","), "k=v"; got != want {
+			t.Errorf("keys = %q, want %q", got, want)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNamespaceIsolatesSiblingKeys(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	a := kvutil.Namespace(db, "a")
+	b := kvutil.Namespace(db, "b")
+
+	if err := kvutil.WithReadWriter(ctx, a, func(ctx context.Context, rw kv.ReadWriter) error {
+		return rw.Set(ctx, "k", strings.NewReader("a-value"))
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := kvutil.WithReader(ctx, b, func(ctx context.Context, r kv.Reader) error {
+		if _, err := r.Get(ctx, "k"); err == nil {
+			t.Errorf("namespace %q saw key written under sibling namespace %q", "b", "a")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
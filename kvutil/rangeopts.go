@@ -0,0 +1,93 @@
+// Copyright (c) 2025 Visvasity LLC
+
+package kvutil
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"iter"
+
+	"github.com/visvasity/kv"
+)
+
+// StreamingMode hints how aggressively a backend should prefetch batches
+// while streaming a range, trading latency for throughput. It is loosely
+// modeled on FoundationDB's GetRange streaming modes. Backends that have no
+// native notion of batching are free to ignore it.
+type StreamingMode int
+
+const (
+	// StreamingModeWantAll hints the whole range will be consumed, so the
+	// backend should fetch it as aggressively as possible.
+	StreamingModeWantAll StreamingMode = iota
+	// StreamingModeIterator hints the caller may stop early, so the backend
+	// should prefetch conservatively.
+	StreamingModeIterator
+	StreamingModeSmall
+	StreamingModeMedium
+	StreamingModeLarge
+)
+
+// RangeOptions refines a range scan beyond the plain begin/end bounds that
+// kv.Ranger takes.
+type RangeOptions struct {
+	// Limit caps the number of key-value pairs returned. Zero means no
+	// limit.
+	Limit int
+	// TargetBytes hints the approximate total size of a single prefetched
+	// batch. Zero means no target.
+	TargetBytes int
+	// Reverse iterates in descending order when true, ascending otherwise.
+	Reverse bool
+	// KeysOnly asks the backend to skip fetching values when it can do so
+	// natively, for callers that only need keys (e.g. Clear).
+	KeysOnly      bool
+	StreamingMode StreamingMode
+}
+
+// RangerExt is implemented by kv.Ranger values (snapshots, transactions,
+// databases) that can serve a RangeOptions-refined scan natively, instead of
+// falling back to the portable GetRange default built on Ascend/Descend.
+// Callers use GetRange rather than asserting for this interface directly.
+type RangerExt interface {
+	RangeExt(ctx context.Context, beg, end string, opts RangeOptions, errp *error) iter.Seq2[string, io.Reader]
+}
+
+// GetRange scans [beg, end) honoring opts, using r's native RangeExt
+// implementation when available and otherwise falling back to a portable
+// default built on top of Ascend/Descend that applies Limit, Reverse and
+// KeysOnly in-process. Callers doing Clear, Backup or large scans should
+// prefer GetRange over Ascend/Descend directly so that backends capable of
+// native pagination (etcd's WithLimit/WithKeysOnly, Pebble's bounded
+// iterator, etc.) can avoid materializing values they don't need.
+func GetRange(ctx context.Context, r kv.Ranger, beg, end string, opts RangeOptions, errp *error) iter.Seq2[string, io.Reader] {
+	if ext, ok := r.(RangerExt); ok {
+		return ext.RangeExt(ctx, beg, end, opts, errp)
+	}
+	return defaultRangeExt(ctx, r, beg, end, opts, errp)
+}
+
+func defaultRangeExt(ctx context.Context, r kv.Ranger, beg, end string, opts RangeOptions, errp *error) iter.Seq2[string, io.Reader] {
+	return func(yield func(string, io.Reader) bool) {
+		seq := r.Ascend(ctx, beg, end, errp)
+		if opts.Reverse {
+			seq = r.Descend(ctx, beg, end, errp)
+		}
+
+		n := 0
+		for k, v := range seq {
+			if opts.Limit > 0 && n >= opts.Limit {
+				return
+			}
+			val := v
+			if opts.KeysOnly {
+				val = bytes.NewReader(nil)
+			}
+			if !yield(k, val) {
+				return
+			}
+			n++
+		}
+	}
+}
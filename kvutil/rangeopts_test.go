@@ -0,0 +1,77 @@
+// Copyright (c) 2025 Visvasity LLC
+
+package kvutil_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/visvasity/kv"
+	"github.com/visvasity/kv/kvutil"
+)
+
+// portableRanger wraps a kv.Reader but deliberately does not implement
+// kvutil.RangerExt, so GetRange against it always exercises the portable
+// defaultRangeExt fallback built on Ascend/Descend, regardless of whether
+// the underlying backend has a native RangeExt.
+type portableRanger struct {
+	kv.Reader
+}
+
+func TestGetRangePortableFallbackHonorsLimitAndKeysOnly(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	putN(t, ctx, db, 5)
+
+	if err := kvutil.WithReader(ctx, db, func(ctx context.Context, r kv.Reader) error {
+		pr := portableRanger{r}
+		if _, ok := any(pr).(kvutil.RangerExt); ok {
+			t.Fatal("portableRanger unexpectedly implements RangerExt; test no longer exercises the fallback")
+		}
+
+		var err error
+		var keys []string
+		for k, v := range kvutil.GetRange(ctx, pr, "", "", kvutil.RangeOptions{Limit: 2, KeysOnly: true}, &err) {
+			keys = append(keys, k)
+			b, _ := io.ReadAll(v)
+			if len(b) != 0 {
+				t.Errorf("KeysOnly returned non-empty value %q for key %q", b, k)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if got, want := strings.Join(keys, ","), "k0,k1"; got != want {
+			t.Errorf("keys = %q, want %q", got, want)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetRangePortableFallbackHonorsReverse(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	putN(t, ctx, db, 3)
+
+	if err := kvutil.WithReader(ctx, db, func(ctx context.Context, r kv.Reader) error {
+		pr := portableRanger{r}
+		var err error
+		var keys []string
+		for k := range kvutil.GetRange(ctx, pr, "", "", kvutil.RangeOptions{Reverse: true}, &err) {
+			keys = append(keys, k)
+		}
+		if err != nil {
+			return err
+		}
+		if got, want := strings.Join(keys, ","), "k2,k1,k0"; got != want {
+			t.Errorf("keys = %q, want %q", got, want)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
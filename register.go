@@ -0,0 +1,89 @@
+// Copyright (c) 2025 Visvasity LLC
+
+package kv
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+)
+
+// Opener opens a Database for the scheme it was registered under, given the
+// remainder of the DSN parsed as a URL.
+type Opener func(ctx context.Context, u *url.URL) (Database, error)
+
+var (
+	driversMu sync.RWMutex
+	drivers   = make(map[string]Opener)
+)
+
+// Register makes a backend available under scheme, so that Open can dispatch
+// DSNs of the form "<scheme>://..." to it. Backend packages are expected to
+// call Register from an init function. Register panics if opener is nil or
+// if a driver is already registered for scheme.
+func Register(scheme string, opener Opener) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+
+	if opener == nil {
+		panic("kv: Register opener is nil")
+	}
+	if _, dup := drivers[scheme]; dup {
+		panic("kv: Register called twice for driver " + scheme)
+	}
+	drivers[scheme] = opener
+}
+
+// Drivers returns the sorted list of scheme names registered with Register.
+func Drivers() []string {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DSNError reports that a DSN passed to Open could not be parsed or did not
+// name a registered driver. Callers can use errors.As to distinguish a
+// malformed configuration from a failure to connect.
+type DSNError struct {
+	DSN string
+	Err error
+}
+
+func (e *DSNError) Error() string {
+	return fmt.Sprintf("kv: invalid dsn %q: %v", e.DSN, e.Err)
+}
+
+func (e *DSNError) Unwrap() error {
+	return e.Err
+}
+
+// Open parses dsn as a URL and dispatches to the Opener registered for its
+// scheme, e.g. "pebble:///var/lib/app/db", "etcd://host:2379/prefix", or
+// "bolt:///tmp/x.db?mode=0600". Returns a *DSNError if dsn cannot be parsed
+// or names a scheme with no registered driver; otherwise returns whatever
+// error the driver's Opener returns.
+func Open(ctx context.Context, dsn string) (Database, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, &DSNError{DSN: dsn, Err: err}
+	}
+	if u.Scheme == "" {
+		return nil, &DSNError{DSN: dsn, Err: fmt.Errorf("dsn has no scheme")}
+	}
+
+	driversMu.RLock()
+	opener, ok := drivers[u.Scheme]
+	driversMu.RUnlock()
+	if !ok {
+		return nil, &DSNError{DSN: dsn, Err: fmt.Errorf("unknown driver %q", u.Scheme)}
+	}
+	return opener(ctx, u)
+}
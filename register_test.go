@@ -0,0 +1,88 @@
+// Copyright (c) 2025 Visvasity LLC
+
+package kv_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/visvasity/kv"
+	_ "github.com/visvasity/kv/kvpebble"
+)
+
+func TestDriversIncludesRegisteredBackends(t *testing.T) {
+	var found bool
+	for _, name := range kv.Drivers() {
+		if name == "pebble" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("kv.Drivers() = %v, want it to include %q", kv.Drivers(), "pebble")
+	}
+}
+
+func TestOpenDispatchesToRegisteredDriver(t *testing.T) {
+	ctx := context.Background()
+	db, err := kv.Open(ctx, "pebble://"+t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	tx, err := db.NewTransaction(ctx)
+	if err != nil {
+		t.Fatalf("NewTransaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+	if err := tx.Set(ctx, "k", strings.NewReader("v")); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	snap, err := db.NewSnapshot(ctx)
+	if err != nil {
+		t.Fatalf("NewSnapshot: %v", err)
+	}
+	defer snap.Discard(ctx)
+	v, err := snap.Get(ctx, "k")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	b, _ := io.ReadAll(v)
+	if string(b) != "v" {
+		t.Errorf("got %q, want %q", b, "v")
+	}
+}
+
+func TestOpenUnknownScheme(t *testing.T) {
+	_, err := kv.Open(context.Background(), "nosuchdriver://somewhere")
+	var dsnErr *kv.DSNError
+	if !errors.As(err, &dsnErr) {
+		t.Fatalf("Open(unknown scheme) error = %v, want *kv.DSNError", err)
+	}
+}
+
+func TestOpenMissingScheme(t *testing.T) {
+	_, err := kv.Open(context.Background(), "/just/a/path")
+	var dsnErr *kv.DSNError
+	if !errors.As(err, &dsnErr) {
+		t.Fatalf("Open(no scheme) error = %v, want *kv.DSNError", err)
+	}
+}
+
+func TestRegisterDuplicatePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Register with an already-registered scheme did not panic")
+		}
+	}()
+	kv.Register("pebble", func(ctx context.Context, u *url.URL) (kv.Database, error) {
+		return nil, nil
+	})
+}